@@ -0,0 +1,87 @@
+package sequence_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/go-spectest/mermaid/sequence"
+)
+
+func TestDiagram_Activate_ErrorPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(d *sequence.Diagram)
+	}{
+		{
+			name: "unbalanced activate",
+			build: func(d *sequence.Diagram) {
+				d.Participant("A").Activate("A")
+			},
+		},
+		{
+			name: "deactivate without activate",
+			build: func(d *sequence.Diagram) {
+				d.Participant("A").Deactivate("A")
+			},
+		},
+		{
+			name: "unclosed rect",
+			build: func(d *sequence.Diagram) {
+				d.RectStart("rgb(0,0,0)")
+			},
+		},
+		{
+			name: "rect end without start",
+			build: func(d *sequence.Diagram) {
+				d.RectEnd()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := sequence.NewDiagram(io.Discard)
+			tt.build(d)
+
+			if err := d.Build(); err == nil {
+				t.Fatalf("Build(): got nil error")
+			}
+			if d.Error() == nil {
+				t.Fatalf("Error(): got nil")
+			}
+		})
+	}
+}
+
+func TestDiagram_NotesAndActivation(t *testing.T) {
+	var buf bytes.Buffer
+	d := sequence.NewDiagram(&buf).
+		Participant("A").
+		Participant("B").
+		NoteLeftOf("A", "pondering").
+		SyncRequestActivate("A", "B", "hello").
+		NoteRightOf("B", "thinking").
+		SyncResponseDeactivate("B", "A", "world").
+		RectStart("rgb(200,200,200)").
+		SyncRequest("A", "B", "inside rect").
+		RectEnd()
+
+	if err := d.Build(); err != nil {
+		t.Fatalf("Build(): %v", err)
+	}
+
+	want := "sequenceDiagram\n" +
+		"    participant A\n" +
+		"    participant B\n" +
+		"    Note left of A: pondering\n" +
+		"    A->>+B: hello\n" +
+		"    Note right of B: thinking\n" +
+		"    B-->>-A: world\n" +
+		"    rect rgb(200,200,200)\n" +
+		"    A->>B: inside rect\n" +
+		"    end"
+	if got := buf.String(); got != want {
+		t.Fatalf("Build() wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package sequence_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/go-spectest/mermaid/sequence"
+)
+
+func TestDiagram_Block_ErrorPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(d *sequence.Diagram)
+	}{
+		{
+			name: "unclosed alt",
+			build: func(d *sequence.Diagram) {
+				d.AltStart("cond")
+			},
+		},
+		{
+			name: "else outside alt",
+			build: func(d *sequence.Diagram) {
+				d.Else("cond")
+			},
+		},
+		{
+			name: "unclosed par",
+			build: func(d *sequence.Diagram) {
+				d.ParStart("desc")
+			},
+		},
+		{
+			name: "and outside par",
+			build: func(d *sequence.Diagram) {
+				d.And("desc")
+			},
+		},
+		{
+			name: "unclosed critical",
+			build: func(d *sequence.Diagram) {
+				d.CriticalStart("desc")
+			},
+		},
+		{
+			name: "option outside critical",
+			build: func(d *sequence.Diagram) {
+				d.Option("cond")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := sequence.NewDiagram(io.Discard)
+			tt.build(d)
+
+			if err := d.Build(); err == nil {
+				t.Fatalf("Build(): got nil error")
+			}
+			if d.Error() == nil {
+				t.Fatalf("Error(): got nil")
+			}
+		})
+	}
+}
+
+func TestDiagram_CriticalBlock(t *testing.T) {
+	var buf bytes.Buffer
+	d := sequence.NewDiagram(&buf).
+		Participant("A").
+		Participant("B").
+		CriticalStart("ensure delivery").
+		SyncRequest("A", "B", "send").
+		Option("network timeout").
+		RequestError("A", "B", "retry").
+		CriticalEnd()
+
+	if err := d.Build(); err != nil {
+		t.Fatalf("Build(): %v", err)
+	}
+
+	want := "sequenceDiagram\n" +
+		"    participant A\n" +
+		"    participant B\n" +
+		"    critical ensure delivery\n" +
+		"    A->>B: send\n" +
+		"    option network timeout\n" +
+		"    A-xB: retry\n" +
+		"    end"
+	if got := buf.String(); got != want {
+		t.Fatalf("Build() wrote %q, want %q", got, want)
+	}
+}
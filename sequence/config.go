@@ -0,0 +1,10 @@
+package sequence
+
+// Config is the configuration for the sequence diagram.
+// Now, Config is not used.
+type Config struct{}
+
+// NewConfig returns a new Config.
+func NewConfig() *Config {
+	return &Config{}
+}
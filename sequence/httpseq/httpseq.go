@@ -0,0 +1,186 @@
+// Package httpseq records real HTTP traffic into a mermaid sequence
+// diagram, for use in spectest and similar tools that want a visual trace
+// of what a test actually did over the wire.
+package httpseq
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/go-spectest/mermaid/sequence"
+)
+
+// contextKey is the type of the key used to stash the in-flight call's
+// participants in a request's context.
+type contextKey struct{}
+
+// call correlates a recorded request with its eventual response.
+type call struct {
+	from, to string
+}
+
+// CallInfo returns the participants recorded for the in-flight call stored
+// in ctx by Recorder, so a label func or NoteFunc can refer to them.
+func CallInfo(ctx context.Context) (from, to string, ok bool) {
+	c, ok := ctx.Value(contextKey{}).(*call)
+	if !ok {
+		return "", "", false
+	}
+	return c.from, c.to, true
+}
+
+// RequestLabelFunc formats the label shown on a request arrow.
+type RequestLabelFunc func(*http.Request) string
+
+// ResponseLabelFunc formats the label shown on a response arrow.
+type ResponseLabelFunc func(*http.Response) string
+
+// NoteFunc inspects req and returns an annotation to record over
+// participant before the request arrow is drawn. ok is false to skip it.
+type NoteFunc func(req *http.Request) (participant, text string, ok bool)
+
+// Recorder is an http.RoundTripper that appends every request/response pair
+// it observes to a *sequence.Diagram, and can also be used as server-side
+// middleware. The zero value is not usable; Diagram must be set.
+type Recorder struct {
+	// Transport is the underlying RoundTripper used to perform requests
+	// recorded by RoundTrip. http.DefaultTransport is used when nil.
+	Transport http.RoundTripper
+	// Diagram receives the recorded messages.
+	Diagram *sequence.Diagram
+	// From and To name the participants used for the arrows. They default
+	// to "Client" and "Server".
+	From, To string
+	// RequestLabel formats the request arrow label. It defaults to
+	// "METHOD path".
+	RequestLabel RequestLabelFunc
+	// ResponseLabel formats the response arrow label. It defaults to the
+	// response status.
+	ResponseLabel ResponseLabelFunc
+	// Note, if set, is called for every request; a true result is recorded
+	// as a NoteOver before the request arrow.
+	Note NoteFunc
+
+	mu sync.Mutex
+}
+
+func (r *Recorder) transport() http.RoundTripper {
+	if r.Transport != nil {
+		return r.Transport
+	}
+	return http.DefaultTransport
+}
+
+func (r *Recorder) from() string {
+	if r.From != "" {
+		return r.From
+	}
+	return "Client"
+}
+
+func (r *Recorder) to() string {
+	if r.To != "" {
+		return r.To
+	}
+	return "Server"
+}
+
+func (r *Recorder) requestLabel(req *http.Request) string {
+	if r.RequestLabel != nil {
+		return r.RequestLabel(req)
+	}
+	return fmt.Sprintf("%s %s", req.Method, req.URL.Path)
+}
+
+func (r *Recorder) responseLabel(resp *http.Response) string {
+	if r.ResponseLabel != nil {
+		return r.ResponseLabel(resp)
+	}
+	return resp.Status
+}
+
+func (r *Recorder) note(req *http.Request) {
+	if r.Note == nil {
+		return
+	}
+	participant, text, ok := r.Note(req)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Diagram.NoteOver(text, participant)
+}
+
+// RoundTrip implements http.RoundTripper, recording the request and its
+// response (or error) to Diagram before returning the underlying
+// Transport's result unchanged. RoundTrip is safe for concurrent use.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	from, to := r.from(), r.to()
+	ctx := context.WithValue(req.Context(), contextKey{}, &call{from: from, to: to})
+	req = req.WithContext(ctx)
+
+	r.note(req)
+
+	r.mu.Lock()
+	r.Diagram.SyncRequest(from, to, r.requestLabel(req))
+	r.mu.Unlock()
+
+	resp, err := r.transport().RoundTrip(req)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.Diagram.RequestError(from, to, err.Error())
+		return resp, err
+	}
+	r.Diagram.SyncResponse(to, from, r.responseLabel(resp))
+	return resp, nil
+}
+
+// statusWriter wraps an http.ResponseWriter to capture the status code a
+// handler writes, so ServerMiddleware can record it after ServeHTTP returns.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// ServerMiddleware wraps next, recording every request it serves to
+// Diagram. It is safe to use next concurrently from multiple goroutines.
+func (r *Recorder) ServerMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		from, to := r.from(), r.to()
+		ctx := context.WithValue(req.Context(), contextKey{}, &call{from: from, to: to})
+		req = req.WithContext(ctx)
+
+		r.note(req)
+
+		r.mu.Lock()
+		r.Diagram.SyncRequest(from, to, r.requestLabel(req))
+		r.mu.Unlock()
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if p := recover(); p != nil {
+				r.Diagram.ResponseError(to, from, fmt.Sprintf("panic: %v", p))
+				panic(p)
+			}
+			r.Diagram.SyncResponse(to, from, r.responseLabel(&http.Response{
+				Status:     fmt.Sprintf("%d %s", sw.status, http.StatusText(sw.status)),
+				StatusCode: sw.status,
+				Header:     sw.Header(),
+				Request:    req,
+			}))
+		}()
+		next.ServeHTTP(sw, req)
+	})
+}
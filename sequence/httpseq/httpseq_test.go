@@ -0,0 +1,109 @@
+package httpseq_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-spectest/mermaid/sequence"
+	"github.com/go-spectest/mermaid/sequence/httpseq"
+)
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	diagram := sequence.NewDiagram(&buf)
+	client := server.Client()
+	client.Transport = &httpseq.Recorder{Diagram: diagram}
+
+	resp, err := client.Get(server.URL + "/brew")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if got, want := resp.StatusCode, http.StatusTeapot; got != want {
+		t.Fatalf("status = %d, want %d", got, want)
+	}
+
+	out := diagram.String()
+	if !strings.Contains(out, "Client->>Server: GET /brew") {
+		t.Errorf("missing request line, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Server-->>Client: 418 I'm a teapot") {
+		t.Errorf("missing response line, got:\n%s", out)
+	}
+}
+
+// TestRecorder_ConcurrentRoundTrip exercises RoundTrip from many goroutines
+// at once. Run with -race to confirm the documented concurrent-safety
+// guarantee holds: every call must produce its own matched request/response
+// pair, with no torn or dropped lines from the shared Diagram.
+func TestRecorder_ConcurrentRoundTrip(t *testing.T) {
+	const n = 50
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	diagram := sequence.NewDiagram(&buf)
+	client := server.Client()
+	client.Transport = &httpseq.Recorder{Diagram: diagram}
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := client.Get(server.URL + "/item/" + strconv.Itoa(i))
+			if err != nil {
+				t.Errorf("Get: %v", err)
+				return
+			}
+			io.Copy(io.Discard, resp.Body) //nolint:errcheck
+			resp.Body.Close()
+		}(i)
+	}
+	wg.Wait()
+
+	out := diagram.String()
+	if got, want := strings.Count(out, "Client->>Server:"), n; got != want {
+		t.Errorf("recorded %d request lines, want %d", got, want)
+	}
+	if got, want := strings.Count(out, "Server-->>Client:"), n; got != want {
+		t.Errorf("recorded %d response lines, want %d", got, want)
+	}
+}
+
+func TestRecorder_ServerMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	diagram := sequence.NewDiagram(&buf)
+	rec := &httpseq.Recorder{Diagram: diagram}
+
+	handler := rec.ServerMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/widgets", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	out := diagram.String()
+	if !strings.Contains(out, "Client->>Server: POST /widgets") {
+		t.Errorf("missing request line, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Server-->>Client: 201 Created") {
+		t.Errorf("missing response line, got:\n%s", out)
+	}
+}
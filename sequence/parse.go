@@ -0,0 +1,201 @@
+package sequence
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// arrowPattern matches a message line such as "A->>+B: do the thing".
+// Longer arrow tokens are listed before their prefixes (e.g. "-->>" before
+// "->>") so the alternation prefers the longest match. Participant IDs are
+// restricted to exclude '-', '>' and ')' so a greedy ID can't swallow part
+// of the arrow itself (e.g. the second "-" of "-->>").
+var arrowPattern = regexp.MustCompile(`^([\w.:]+)(-->>|->>|--x|-x|--\)|-\))([+-]?)([\w.:]+):\s(.*)$`)
+
+var (
+	participantPattern = regexp.MustCompile(`^participant\s+(\S+)(?:\s+as\s+(.+))?$`)
+	actorPattern       = regexp.MustCompile(`^actor\s+(\S+)(?:\s+as\s+(.+))?$`)
+	noteOverPattern    = regexp.MustCompile(`^Note over (\S+(?:\s*,\s*\S+)*):\s(.*)$`)
+	noteLeftPattern    = regexp.MustCompile(`^Note left of (\S+):\s(.*)$`)
+	noteRightPattern   = regexp.MustCompile(`^Note right of (\S+):\s(.*)$`)
+	autonumberFrom     = regexp.MustCompile(`^autonumber\s+(\d+)\s+(\d+)$`)
+)
+
+// Parse reads a Mermaid sequence diagram from r and rebuilds the equivalent
+// *Diagram, so that d.String() reproduces semantically identical output.
+//
+// Unknown directives are recorded as the diagram's build error (see
+// Diagram.Error) with the offending line number, rather than causing Parse
+// to panic. Parse tolerates both LF and CRLF line endings.
+func Parse(r io.Reader) (*Diagram, error) {
+	d := NewDiagram(io.Discard)
+
+	// stack tracks the kind of every currently open block so a bare "end"
+	// line can be routed to the right *End method. Unlike d.blocks, it
+	// also covers loop/break/rect/box, which d.blocks doesn't track.
+	var stack []string
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	header := false
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if !header {
+			if trimmed == "" {
+				continue
+			}
+			if trimmed != "sequenceDiagram" {
+				d.addErr(fmt.Errorf("sequence: line %d: expected %q header, got %q", lineNo, "sequenceDiagram", trimmed))
+				return d, d.Error()
+			}
+			header = true
+			continue
+		}
+
+		if trimmed == "" {
+			d.LF()
+			continue
+		}
+
+		switch {
+		case trimmed == "end":
+			if len(stack) == 0 {
+				d.addErr(fmt.Errorf("sequence: line %d: unexpected %q with no open block", lineNo, "end"))
+				continue
+			}
+			kind := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			switch kind {
+			case "loop":
+				d.LoopEnd()
+			case "break":
+				d.BreakEnd()
+			case "alt":
+				d.AltEnd()
+			case "opt":
+				d.OptEnd()
+			case "par":
+				d.ParEnd()
+			case "critical":
+				d.CriticalEnd()
+			case "rect":
+				d.RectEnd()
+			case "box":
+				d.BoxEnd()
+			}
+		case trimmed == "autonumber":
+			d.Autonumber()
+		case autonumberFrom.MatchString(trimmed):
+			m := autonumberFrom.FindStringSubmatch(trimmed)
+			start, _ := strconv.Atoi(m[1])
+			step, _ := strconv.Atoi(m[2])
+			d.AutonumberFrom(start, step)
+		case participantPattern.MatchString(trimmed):
+			m := participantPattern.FindStringSubmatch(trimmed)
+			if m[2] != "" {
+				d.ParticipantAs(m[1], m[2])
+			} else {
+				d.Participant(m[1])
+			}
+		case actorPattern.MatchString(trimmed):
+			m := actorPattern.FindStringSubmatch(trimmed)
+			if m[2] != "" {
+				d.ActorAs(m[1], m[2])
+			} else {
+				d.Actor(m[1])
+			}
+		case strings.HasPrefix(trimmed, "box "):
+			d.BoxStart(strings.TrimPrefix(trimmed, "box "), "")
+			stack = append(stack, "box")
+		case strings.HasPrefix(trimmed, "loop "):
+			d.LoopStart(strings.TrimPrefix(trimmed, "loop "))
+			stack = append(stack, "loop")
+		case strings.HasPrefix(trimmed, "break "):
+			d.BreakStart(strings.TrimPrefix(trimmed, "break "))
+			stack = append(stack, "break")
+		case strings.HasPrefix(trimmed, "alt "):
+			d.AltStart(strings.TrimPrefix(trimmed, "alt "))
+			stack = append(stack, "alt")
+		case strings.HasPrefix(trimmed, "else "):
+			d.Else(strings.TrimPrefix(trimmed, "else "))
+		case strings.HasPrefix(trimmed, "opt "):
+			d.OptStart(strings.TrimPrefix(trimmed, "opt "))
+			stack = append(stack, "opt")
+		case strings.HasPrefix(trimmed, "par "):
+			d.ParStart(strings.TrimPrefix(trimmed, "par "))
+			stack = append(stack, "par")
+		case strings.HasPrefix(trimmed, "and "):
+			d.And(strings.TrimPrefix(trimmed, "and "))
+		case strings.HasPrefix(trimmed, "critical "):
+			d.CriticalStart(strings.TrimPrefix(trimmed, "critical "))
+			stack = append(stack, "critical")
+		case strings.HasPrefix(trimmed, "option "):
+			d.Option(strings.TrimPrefix(trimmed, "option "))
+		case strings.HasPrefix(trimmed, "rect "):
+			d.RectStart(strings.TrimPrefix(trimmed, "rect "))
+			stack = append(stack, "rect")
+		case strings.HasPrefix(trimmed, "activate "):
+			d.Activate(strings.TrimPrefix(trimmed, "activate "))
+		case strings.HasPrefix(trimmed, "deactivate "):
+			d.Deactivate(strings.TrimPrefix(trimmed, "deactivate "))
+		case noteOverPattern.MatchString(trimmed):
+			m := noteOverPattern.FindStringSubmatch(trimmed)
+			participants := strings.Split(m[1], ",")
+			for i := range participants {
+				participants[i] = strings.TrimSpace(participants[i])
+			}
+			d.NoteOver(m[2], participants...)
+		case noteLeftPattern.MatchString(trimmed):
+			m := noteLeftPattern.FindStringSubmatch(trimmed)
+			d.NoteLeftOf(m[1], m[2])
+		case noteRightPattern.MatchString(trimmed):
+			m := noteRightPattern.FindStringSubmatch(trimmed)
+			d.NoteRightOf(m[1], m[2])
+		case arrowPattern.MatchString(trimmed):
+			m := arrowPattern.FindStringSubmatch(trimmed)
+			from, arrow, marker, to, message := m[1], m[2], m[3], m[4], m[5]
+			switch arrow {
+			case "->>":
+				if marker == "+" {
+					d.SyncRequestActivate(from, to, message)
+				} else {
+					d.SyncRequest(from, to, message)
+				}
+			case "-->>":
+				if marker == "-" {
+					d.SyncResponseDeactivate(from, to, message)
+				} else {
+					d.SyncResponse(from, to, message)
+				}
+			case "-x":
+				d.RequestError(from, to, message)
+			case "--x":
+				d.ResponseError(from, to, message)
+			case "-)":
+				d.AsyncRequest(from, to, message)
+			case "--)":
+				d.AsyncResponse(from, to, message)
+			}
+		default:
+			d.addErr(fmt.Errorf("sequence: line %d: unknown directive %q", lineNo, trimmed))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return d, fmt.Errorf("sequence: read diagram: %w", err)
+	}
+	if len(stack) > 0 {
+		// Unclosed blocks are also caught here so Parse itself reports
+		// them even before Build's own validation runs.
+		d.addErr(fmt.Errorf("sequence: unexpected end of input with %d block(s) still open", len(stack)))
+	}
+
+	return d, d.Error()
+}
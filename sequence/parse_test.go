@@ -0,0 +1,124 @@
+package sequence_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-spectest/mermaid/sequence"
+)
+
+// Example_parse builds a diagram exercising most of the grammar Parse
+// understands, reads it back with Parse, and checks that re-emitting the
+// parsed diagram reproduces the original text byte for byte.
+func Example_parse() {
+	var buf bytes.Buffer
+	sequence.NewDiagram(&buf).
+		Participant("Alice").
+		ActorAs("B", "Bob").
+		ParticipantAs("Carol", "Dr. Carol").
+		AutonumberFrom(10, 2).
+		BoxStart("Team", "Aqua").
+		Participant("Dave").
+		BoxEnd().
+		SyncRequestActivate("Alice", "B", "hello").
+		NoteOver("thinking", "B").
+		NoteLeftOf("Alice", "waiting").
+		NoteRightOf("B", "pondering").
+		AltStart("all good").
+		SyncResponseDeactivate("B", "Alice", "world").
+		Else("something went wrong").
+		RequestError("Alice", "B", "oops").
+		ResponseError("B", "Alice", "nope").
+		AltEnd().
+		OptStart("maybe").
+		AsyncRequest("Alice", "Carol", "fire and forget").
+		AsyncResponse("Carol", "Alice", "ack").
+		OptEnd().
+		CriticalStart("ensure delivery").
+		SyncRequest("Alice", "Dave", "send").
+		Option("network timeout").
+		RequestError("Alice", "Dave", "retry").
+		CriticalEnd().
+		RectStart("rgb(0,255,0)").
+		LoopStart("until done").
+		SyncRequest("Alice", "Carol", "again").
+		LoopEnd().
+		RectEnd().
+		Build() //nolint
+
+	original := buf.String()
+
+	parsed, err := sequence.Parse(strings.NewReader(original))
+	if err != nil {
+		fmt.Println("parse error:", err)
+		return
+	}
+
+	fmt.Println(parsed.String() == original)
+	// Output:
+	// true
+}
+
+// TestParse_RoundTripsCRLF checks that a Windows-authored (CRLF) file
+// parses the same as its LF equivalent.
+func TestParse_RoundTripsCRLF(t *testing.T) {
+	lf := "sequenceDiagram\n    participant A\n    A->>A: hi\n"
+	crlf := strings.ReplaceAll(lf, "\n", "\r\n")
+
+	lfDiagram, err := sequence.Parse(strings.NewReader(lf))
+	if err != nil {
+		t.Fatalf("Parse(LF): %v", err)
+	}
+	crlfDiagram, err := sequence.Parse(strings.NewReader(crlf))
+	if err != nil {
+		t.Fatalf("Parse(CRLF): %v", err)
+	}
+
+	if lfDiagram.String() != crlfDiagram.String() {
+		t.Fatalf("CRLF diagram = %q, want %q", crlfDiagram.String(), lfDiagram.String())
+	}
+}
+
+// TestParse_Errors checks that malformed input is reported through
+// Diagram.Error (and Parse's own error return) instead of panicking.
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "missing header",
+			input: "A->>B: hi\n",
+		},
+		{
+			name:  "unknown directive",
+			input: "sequenceDiagram\n    frobnicate A\n",
+		},
+		{
+			name:  "end with no open block",
+			input: "sequenceDiagram\n    end\n",
+		},
+		{
+			name:  "else outside alt",
+			input: "sequenceDiagram\n    else nope\n",
+		},
+		{
+			name:  "unclosed block",
+			input: "sequenceDiagram\n    loop forever\n    A->>B: hi\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := sequence.Parse(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatalf("Parse(%q): got nil error", tt.input)
+			}
+			if d.Error() == nil {
+				t.Fatalf("Parse(%q): d.Error() is nil", tt.input)
+			}
+		})
+	}
+}
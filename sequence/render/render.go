@@ -0,0 +1,167 @@
+// Package render renders mermaid diagrams (sequence, flowchart, ER, pie,
+// ...) to SVG or PNG by shelling out to mermaid-cli (mmdc), caching the
+// result on disk so repeated renders of the same diagram in CI are fast.
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Stringer is implemented by any diagram builder that can render itself to
+// mermaid source text, such as *sequence.Diagram.
+type Stringer interface {
+	String() string
+}
+
+// ErrMermaidCLINotFound is returned by RenderSVG/RenderPNG when mmdc cannot
+// be found, so callers can fall back to embedding the raw mermaid block.
+var ErrMermaidCLINotFound = errors.New("render: mmdc (mermaid-cli) not found on PATH")
+
+// Theme is a mermaid-cli theme name.
+type Theme string
+
+// Themes supported by mermaid-cli.
+const (
+	ThemeDefault Theme = "default"
+	ThemeDark    Theme = "dark"
+	ThemeForest  Theme = "forest"
+	ThemeNeutral Theme = "neutral"
+)
+
+// Renderer renders mermaid diagrams to SVG or PNG using the mmdc
+// (mermaid-cli) subprocess.
+type Renderer struct {
+	// Bin is the mmdc binary to run, resolved via exec.LookPath. Defaults
+	// to "mmdc".
+	Bin string
+	// PuppeteerConfigPath is passed to mmdc as --puppeteerConfigFile, if set.
+	PuppeteerConfigPath string
+	// Theme is passed to mmdc as --theme. Defaults to ThemeDefault.
+	Theme Theme
+	// Background is passed to mmdc as --backgroundColor, if set.
+	Background string
+	// CacheDir overrides where rendered output is cached. Defaults to
+	// filepath.Join(os.UserCacheDir(), "mermaid-render").
+	CacheDir string
+}
+
+// NewRenderer returns a Renderer configured with mermaid-cli's defaults.
+func NewRenderer() *Renderer {
+	return &Renderer{
+		Bin:   "mmdc",
+		Theme: ThemeDefault,
+	}
+}
+
+func (r *Renderer) bin() string {
+	if r.Bin != "" {
+		return r.Bin
+	}
+	return "mmdc"
+}
+
+func (r *Renderer) theme() Theme {
+	if r.Theme != "" {
+		return r.Theme
+	}
+	return ThemeDefault
+}
+
+func (r *Renderer) cacheDir() (string, error) {
+	if r.CacheDir != "" {
+		return r.CacheDir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("render: resolve cache dir: %w", err)
+	}
+	return filepath.Join(dir, "mermaid-render"), nil
+}
+
+// RenderSVG renders d to SVG, writing the result to w.
+func (r *Renderer) RenderSVG(d Stringer, w io.Writer) error {
+	return r.render(d, w, "svg")
+}
+
+// RenderPNG renders d to PNG, writing the result to w.
+func (r *Renderer) RenderPNG(d Stringer, w io.Writer) error {
+	return r.render(d, w, "png")
+}
+
+// render renders d to format ("svg" or "png"), using the on-disk cache
+// when the diagram source and options haven't changed.
+func (r *Renderer) render(d Stringer, w io.Writer, format string) error {
+	source := d.String()
+
+	dir, err := r.cacheDir()
+	if err != nil {
+		return err
+	}
+	cachePath := filepath.Join(dir, r.cacheKey(source, format)+"."+format)
+
+	if cached, err := os.ReadFile(cachePath); err == nil {
+		_, err := w.Write(cached)
+		return err
+	}
+
+	bin, err := exec.LookPath(r.bin())
+	if err != nil {
+		return ErrMermaidCLINotFound
+	}
+
+	tmpDir, err := os.MkdirTemp("", "mermaid-render-*")
+	if err != nil {
+		return fmt.Errorf("render: create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	inPath := filepath.Join(tmpDir, "diagram.mmd")
+	if err := os.WriteFile(inPath, []byte(source), 0o600); err != nil {
+		return fmt.Errorf("render: write diagram source: %w", err)
+	}
+	outPath := filepath.Join(tmpDir, "diagram."+format)
+
+	args := []string{"-i", inPath, "-o", outPath, "-t", string(r.theme())}
+	if r.PuppeteerConfigPath != "" {
+		args = append(args, "-p", r.PuppeteerConfigPath)
+	}
+	if r.Background != "" {
+		args = append(args, "-b", r.Background)
+	}
+
+	cmd := exec.Command(bin, args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("render: run %s: %w: %s", bin, err, out)
+	}
+
+	rendered, err := os.ReadFile(outPath)
+	if err != nil {
+		return fmt.Errorf("render: read %s output: %w", format, err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("render: create cache dir: %w", err)
+	}
+	if err := os.WriteFile(cachePath, rendered, 0o600); err != nil {
+		return fmt.Errorf("render: write cache file: %w", err)
+	}
+
+	_, err = w.Write(rendered)
+	return err
+}
+
+// cacheKey returns a filename-safe cache key derived from the diagram
+// source and the rendering options that affect its output.
+func (r *Renderer) cacheKey(source, format string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s", source, format, r.theme(), r.Background, r.PuppeteerConfigPath)
+	return hex.EncodeToString(h.Sum(nil))
+}
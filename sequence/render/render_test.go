@@ -0,0 +1,122 @@
+package render_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-spectest/mermaid/sequence/render"
+)
+
+type fakeDiagram string
+
+func (d fakeDiagram) String() string { return string(d) }
+
+// writeFakeMMDC installs a stand-in "mmdc" on PATH that writes a fixed
+// payload to whatever file follows -o, and appends a line to countFile every
+// time it runs, so tests can assert how many times it was actually invoked.
+func writeFakeMMDC(t *testing.T, countFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "mmdc")
+	content := fmt.Sprintf(`#!/bin/sh
+echo run >> %q
+out=""
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -o) out="$2"; shift 2 ;;
+    *) shift ;;
+  esac
+done
+echo "rendered" > "$out"
+`, countFile)
+	if err := os.WriteFile(script, []byte(content), 0o755); err != nil {
+		t.Fatalf("write fake mmdc: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func runCount(t *testing.T, countFile string) int {
+	t.Helper()
+	b, err := os.ReadFile(countFile)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatalf("read count file: %v", err)
+	}
+	return bytes.Count(b, []byte("\n"))
+}
+
+func TestRenderer_MermaidCLINotFound(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	r := render.NewRenderer()
+	r.CacheDir = t.TempDir()
+
+	var buf bytes.Buffer
+	err := r.RenderSVG(fakeDiagram("sequenceDiagram"), &buf)
+	if err != render.ErrMermaidCLINotFound { //nolint:errorlint
+		t.Fatalf("err = %v, want %v", err, render.ErrMermaidCLINotFound)
+	}
+}
+
+func TestRenderer_CachesRenders(t *testing.T) {
+	countFile := filepath.Join(t.TempDir(), "count")
+	writeFakeMMDC(t, countFile)
+
+	r := render.NewRenderer()
+	r.CacheDir = t.TempDir()
+
+	diagram := fakeDiagram("sequenceDiagram\n    A->>B: hi")
+
+	var first bytes.Buffer
+	if err := r.RenderSVG(diagram, &first); err != nil {
+		t.Fatalf("RenderSVG: %v", err)
+	}
+	if got, want := first.String(), "rendered\n"; got != want {
+		t.Fatalf("first render = %q, want %q", got, want)
+	}
+	if got, want := runCount(t, countFile), 1; got != want {
+		t.Fatalf("mmdc ran %d times, want %d", got, want)
+	}
+
+	var second bytes.Buffer
+	if err := r.RenderSVG(diagram, &second); err != nil {
+		t.Fatalf("RenderSVG (cached): %v", err)
+	}
+	if got, want := second.String(), "rendered\n"; got != want {
+		t.Fatalf("cached render = %q, want %q", got, want)
+	}
+	if got, want := runCount(t, countFile), 1; got != want {
+		t.Fatalf("mmdc ran %d times after cache hit, want %d", got, want)
+	}
+}
+
+func TestRenderer_CacheKeyVariesWithOptions(t *testing.T) {
+	countFile := filepath.Join(t.TempDir(), "count")
+	writeFakeMMDC(t, countFile)
+
+	diagram := fakeDiagram("sequenceDiagram\n    A->>B: hi")
+	cacheDir := t.TempDir()
+
+	dark := render.NewRenderer()
+	dark.CacheDir = cacheDir
+	dark.Theme = render.ThemeDark
+	if err := dark.RenderSVG(diagram, &bytes.Buffer{}); err != nil {
+		t.Fatalf("RenderSVG (dark): %v", err)
+	}
+
+	forest := render.NewRenderer()
+	forest.CacheDir = cacheDir
+	forest.Theme = render.ThemeForest
+	if err := forest.RenderSVG(diagram, &bytes.Buffer{}); err != nil {
+		t.Fatalf("RenderSVG (forest): %v", err)
+	}
+
+	if got, want := runCount(t, countFile), 2; got != want {
+		t.Fatalf("mmdc ran %d times for two distinct themes, want %d", got, want)
+	}
+}
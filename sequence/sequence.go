@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"sort"
 	"strings"
 )
 
@@ -18,6 +19,54 @@ type Diagram struct {
 	dest io.Writer
 	// err manages errors that occur in all parts of the sequence diagram building.
 	err error
+	// activated tracks how many times each participant has been activated
+	// without a matching deactivation yet.
+	activated map[string]int
+	// rectDepth tracks how many rect blocks are currently open.
+	rectDepth int
+	// blocks tracks the stack of currently open alt/opt/par/critical blocks,
+	// so Else/And/Option and the matching *End call can be validated.
+	blocks []blockKind
+}
+
+// blockKind identifies the kind of control-flow block on the block stack.
+type blockKind string
+
+const (
+	blockAlt      blockKind = "alt"
+	blockOpt      blockKind = "opt"
+	blockPar      blockKind = "par"
+	blockCritical blockKind = "critical"
+)
+
+// pushBlock opens a new control-flow block of kind.
+func (d *Diagram) pushBlock(kind blockKind) {
+	d.blocks = append(d.blocks, kind)
+}
+
+// currentBlock returns the innermost open block kind, or "" if none is open.
+func (d *Diagram) currentBlock() blockKind {
+	if len(d.blocks) == 0 {
+		return ""
+	}
+	return d.blocks[len(d.blocks)-1]
+}
+
+// popBlock closes the innermost open block, failing if it isn't of kind.
+func (d *Diagram) popBlock(kind blockKind) {
+	if d.currentBlock() != kind {
+		d.addErr(fmt.Errorf("sequence: unbalanced %q block: innermost open block is %q", kind, d.currentBlock()))
+		return
+	}
+	d.blocks = d.blocks[:len(d.blocks)-1]
+}
+
+// addErr records err as the diagram's build error, keeping the first one
+// reported so later, likely cascading, errors don't hide the root cause.
+func (d *Diagram) addErr(err error) {
+	if d.err == nil {
+		d.err = err
+	}
 }
 
 // NewDiagram returns a new Diagram.
@@ -35,6 +84,171 @@ func NewDiagram(w io.Writer, config ...*Config) *Diagram {
 	}
 }
 
+// Participant declares a participant in the sequence diagram.
+func (d *Diagram) Participant(id string) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    participant %s", id))
+	return d
+}
+
+// ParticipantAs declares a participant, displayed under the alias display.
+func (d *Diagram) ParticipantAs(id, display string) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    participant %s as %s", id, display))
+	return d
+}
+
+// Actor declares a participant rendered as an actor (stick figure) rather
+// than a box.
+func (d *Diagram) Actor(id string) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    actor %s", id))
+	return d
+}
+
+// ActorAs declares an actor, displayed under the alias display.
+func (d *Diagram) ActorAs(id, display string) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    actor %s as %s", id, display))
+	return d
+}
+
+// BoxStart starts a colored box grouping the participants declared within
+// it. color may be empty to use Mermaid's default.
+func (d *Diagram) BoxStart(title, color string) *Diagram {
+	if color == "" {
+		d.body = append(d.body, fmt.Sprintf("    box %s", title))
+		return d
+	}
+	d.body = append(d.body, fmt.Sprintf("    box %s %s", color, title))
+	return d
+}
+
+// BoxEnd ends a box started by BoxStart.
+func (d *Diagram) BoxEnd() *Diagram {
+	d.body = append(d.body, "    end")
+	return d
+}
+
+// Autonumber turns on automatic numbering of messages.
+func (d *Diagram) Autonumber() *Diagram {
+	d.body = append(d.body, "    autonumber")
+	return d
+}
+
+// AutonumberFrom turns on automatic numbering of messages, starting at start
+// and incrementing by step.
+func (d *Diagram) AutonumberFrom(start, step int) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    autonumber %d %d", start, step))
+	return d
+}
+
+// LoopStart starts a loop block.
+func (d *Diagram) LoopStart(desc string) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    loop %s", desc))
+	return d
+}
+
+// LoopEnd ends a loop block.
+func (d *Diagram) LoopEnd() *Diagram {
+	d.body = append(d.body, "    end")
+	return d
+}
+
+// BreakStart starts a break block.
+func (d *Diagram) BreakStart(desc string) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    break %s", desc))
+	return d
+}
+
+// BreakEnd ends a break block.
+func (d *Diagram) BreakEnd() *Diagram {
+	d.body = append(d.body, "    end")
+	return d
+}
+
+// AltStart starts an alt block, the first branch of which runs when cond holds.
+func (d *Diagram) AltStart(cond string) *Diagram {
+	d.pushBlock(blockAlt)
+	d.body = append(d.body, fmt.Sprintf("    alt %s", cond))
+	return d
+}
+
+// Else starts the next branch of the innermost open alt block.
+func (d *Diagram) Else(cond string) *Diagram {
+	if d.currentBlock() != blockAlt {
+		d.addErr(fmt.Errorf("sequence: Else() called outside of an AltStart() block"))
+		return d
+	}
+	d.body = append(d.body, fmt.Sprintf("    else %s", cond))
+	return d
+}
+
+// AltEnd ends an alt block started by AltStart.
+func (d *Diagram) AltEnd() *Diagram {
+	d.popBlock(blockAlt)
+	d.body = append(d.body, "    end")
+	return d
+}
+
+// OptStart starts an opt block, which runs when cond holds.
+func (d *Diagram) OptStart(cond string) *Diagram {
+	d.pushBlock(blockOpt)
+	d.body = append(d.body, fmt.Sprintf("    opt %s", cond))
+	return d
+}
+
+// OptEnd ends an opt block started by OptStart.
+func (d *Diagram) OptEnd() *Diagram {
+	d.popBlock(blockOpt)
+	d.body = append(d.body, "    end")
+	return d
+}
+
+// ParStart starts a par block whose branches run concurrently.
+func (d *Diagram) ParStart(desc string) *Diagram {
+	d.pushBlock(blockPar)
+	d.body = append(d.body, fmt.Sprintf("    par %s", desc))
+	return d
+}
+
+// And starts the next concurrent branch of the innermost open par block.
+func (d *Diagram) And(desc string) *Diagram {
+	if d.currentBlock() != blockPar {
+		d.addErr(fmt.Errorf("sequence: And() called outside of a ParStart() block"))
+		return d
+	}
+	d.body = append(d.body, fmt.Sprintf("    and %s", desc))
+	return d
+}
+
+// ParEnd ends a par block started by ParStart.
+func (d *Diagram) ParEnd() *Diagram {
+	d.popBlock(blockPar)
+	d.body = append(d.body, "    end")
+	return d
+}
+
+// CriticalStart starts a critical block that must run, with optional options.
+func (d *Diagram) CriticalStart(desc string) *Diagram {
+	d.pushBlock(blockCritical)
+	d.body = append(d.body, fmt.Sprintf("    critical %s", desc))
+	return d
+}
+
+// Option starts an alternative option branch of the innermost open critical block.
+func (d *Diagram) Option(cond string) *Diagram {
+	if d.currentBlock() != blockCritical {
+		d.addErr(fmt.Errorf("sequence: Option() called outside of a CriticalStart() block"))
+		return d
+	}
+	d.body = append(d.body, fmt.Sprintf("    option %s", cond))
+	return d
+}
+
+// CriticalEnd ends a critical block started by CriticalStart.
+func (d *Diagram) CriticalEnd() *Diagram {
+	d.popBlock(blockCritical)
+	d.body = append(d.body, "    end")
+	return d
+}
+
 // String returns the sequence diagram body.
 func (d *Diagram) String() string {
 	return strings.Join(d.body, lineFeed())
@@ -47,15 +261,38 @@ func (d *Diagram) Error() error {
 
 // Build writes the sequence diagram body to the output destination.
 func (d *Diagram) Build() error {
+	d.validate()
+	if d.err != nil {
+		return fmt.Errorf("sequence: invalid diagram: %w", d.err)
+	}
 	if _, err := fmt.Fprint(d.dest, d.String()); err != nil {
-		if d.err != nil {
-			return fmt.Errorf("failed to write markdown text: %w: %s", err, d.err.Error()) //nolint:wrapcheck
-		}
 		return fmt.Errorf("failed to write markdown text: %w", err)
 	}
 	return nil
 }
 
+// validate checks the diagram for unbalanced blocks and records any
+// problem it finds as the diagram's build error.
+func (d *Diagram) validate() {
+	participants := make([]string, 0, len(d.activated))
+	for participant := range d.activated {
+		participants = append(participants, participant)
+	}
+	sort.Strings(participants)
+	for _, participant := range participants {
+		if d.activated[participant] > 0 {
+			d.addErr(fmt.Errorf("sequence: %s is activated but never deactivated", participant))
+			return
+		}
+	}
+	if d.rectDepth > 0 {
+		d.addErr(fmt.Errorf("sequence: %d rect block(s) left open", d.rectDepth))
+	}
+	if len(d.blocks) > 0 {
+		d.addErr(fmt.Errorf("sequence: %d block(s) left open, innermost is %q", len(d.blocks), d.currentBlock()))
+	}
+}
+
 // SyncRequest add a request to the sequence diagram.
 func (d *Diagram) SyncRequest(from, to, message string) *Diagram {
 	d.body = append(d.body, fmt.Sprintf("    %s->>%s: %s", from, to, message))
@@ -122,6 +359,85 @@ func (d *Diagram) AsyncResponsef(from, to, format string, args ...any) *Diagram
 	return d.AsyncResponse(from, to, fmt.Sprintf(format, args...))
 }
 
+// NoteOver adds a note spanning one or more participants.
+func (d *Diagram) NoteOver(text string, participants ...string) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    Note over %s: %s", strings.Join(participants, ","), text))
+	return d
+}
+
+// NoteLeftOf adds a note to the left of participant.
+func (d *Diagram) NoteLeftOf(participant, text string) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    Note left of %s: %s", participant, text))
+	return d
+}
+
+// NoteRightOf adds a note to the right of participant.
+func (d *Diagram) NoteRightOf(participant, text string) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    Note right of %s: %s", participant, text))
+	return d
+}
+
+// Activate shows an activation bar on participant.
+func (d *Diagram) Activate(participant string) *Diagram {
+	if d.activated == nil {
+		d.activated = make(map[string]int)
+	}
+	d.activated[participant]++
+	d.body = append(d.body, fmt.Sprintf("    activate %s", participant))
+	return d
+}
+
+// Deactivate ends the activation bar on participant started by Activate.
+func (d *Diagram) Deactivate(participant string) *Diagram {
+	if d.activated[participant] <= 0 {
+		d.addErr(fmt.Errorf("sequence: Deactivate(%q) without matching Activate", participant))
+		return d
+	}
+	d.activated[participant]--
+	d.body = append(d.body, fmt.Sprintf("    deactivate %s", participant))
+	return d
+}
+
+// SyncRequestActivate is shorthand for SyncRequest followed by Activate on to.
+func (d *Diagram) SyncRequestActivate(from, to, message string) *Diagram {
+	d.body = append(d.body, fmt.Sprintf("    %s->>+%s: %s", from, to, message))
+	if d.activated == nil {
+		d.activated = make(map[string]int)
+	}
+	d.activated[to]++
+	return d
+}
+
+// SyncResponseDeactivate is shorthand for SyncResponse followed by Deactivate on from.
+func (d *Diagram) SyncResponseDeactivate(from, to, message string) *Diagram {
+	if d.activated[from] <= 0 {
+		d.addErr(fmt.Errorf("sequence: SyncResponseDeactivate(%q) without matching Activate", from))
+		return d
+	}
+	d.activated[from]--
+	d.body = append(d.body, fmt.Sprintf("    %s-->>-%s: %s", from, to, message))
+	return d
+}
+
+// RectStart starts a background-highlighted rect block in color, e.g.
+// "rgb(0, 255, 0)" or "rgba(0, 255, 0, 0.1)".
+func (d *Diagram) RectStart(color string) *Diagram {
+	d.rectDepth++
+	d.body = append(d.body, fmt.Sprintf("    rect %s", color))
+	return d
+}
+
+// RectEnd ends a rect block started by RectStart.
+func (d *Diagram) RectEnd() *Diagram {
+	if d.rectDepth <= 0 {
+		d.addErr(fmt.Errorf("sequence: RectEnd() without matching RectStart"))
+		return d
+	}
+	d.rectDepth--
+	d.body = append(d.body, "    end")
+	return d
+}
+
 func (d *Diagram) LF() *Diagram {
 	d.body = append(d.body, "")
 	return d
@@ -0,0 +1,45 @@
+package sequence_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/go-spectest/mermaid/sequence"
+)
+
+func TestDiagram_Build_ValidDiagram(t *testing.T) {
+	d := sequence.NewDiagram(io.Discard).
+		Participant("A").
+		Participant("B").
+		Activate("A").
+		SyncRequest("A", "B", "hi").
+		Deactivate("A")
+
+	if err := d.Build(); err != nil {
+		t.Fatalf("Build(): %v", err)
+	}
+	if err := d.Error(); err != nil {
+		t.Fatalf("Error(): %v", err)
+	}
+}
+
+// TestDiagram_MixedExplicitAndImplicitParticipants checks that a diagram
+// mixing explicit Participant/ParticipantAs declarations with plain
+// messages to never-declared ids builds without error, matching Mermaid's
+// own implicit-participant behaviour: an id used for the first time in a
+// message is simply added to the cast, not treated as a typo.
+func TestDiagram_MixedExplicitAndImplicitParticipants(t *testing.T) {
+	d := sequence.NewDiagram(io.Discard).
+		Participant("A").
+		ParticipantAs("B", "Bob").
+		AutonumberFrom(5, 2).
+		SyncRequest("A", "B", "hi").
+		SyncResponse("B", "C", "forwarded") // C was never declared.
+
+	if err := d.Build(); err != nil {
+		t.Fatalf("Build(): %v", err)
+	}
+	if err := d.Error(); err != nil {
+		t.Fatalf("Error(): %v", err)
+	}
+}